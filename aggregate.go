@@ -0,0 +1,36 @@
+package mapcidr
+
+import "net"
+
+// Aggregate coalesces overlapping and adjacent CIDRs into the smallest
+// equivalent set of CIDR blocks. IPv4 and IPv6 inputs are segregated and
+// aggregated independently of each other; the returned slice is sorted,
+// non-overlapping, and covers exactly the same addresses as the union of
+// the inputs.
+func Aggregate(cidrs []string) ([]*net.IPNet, error) {
+	var v4Ranges, v6Ranges []ipRange
+
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+
+		r := cidrToRange(network)
+		if r.bits == 32 {
+			v4Ranges = append(v4Ranges, r)
+		} else {
+			v6Ranges = append(v6Ranges, r)
+		}
+	}
+
+	var aggregated []*net.IPNet
+	for _, r := range mergeRanges(v4Ranges) {
+		aggregated = append(aggregated, rangeToCIDRs(r)...)
+	}
+	for _, r := range mergeRanges(v6Ranges) {
+		aggregated = append(aggregated, rangeToCIDRs(r)...)
+	}
+
+	return aggregated, nil
+}
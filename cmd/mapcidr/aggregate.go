@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/mapcidr"
+)
+
+// aggregateOptions contains cli options for the aggregate command
+type aggregateOptions struct {
+	cidr     cidrFlag
+	fileCidr string
+	output   outputFlag
+	silent   bool
+}
+
+func runAggregate(args []string) {
+	options := &aggregateOptions{}
+
+	flagSet := flag.NewFlagSet("aggregate", flag.ExitOnError)
+	flagSet.Var(&options.cidr, "cidr", "CIDR to process (repeatable)")
+	flagSet.StringVar(&options.fileCidr, "l", "", "File containing CIDR")
+	flagSet.Var(&options.output, "o", "Output target, e.g. type=json,dest=out.json (repeatable)")
+	flagSet.BoolVar(&options.silent, "silent", false, "Silent mode")
+	flagSet.Parse(args)
+
+	positional := flagSet.Args()
+
+	configureOutput(options.silent)
+	showBanner()
+
+	if len(options.cidr) == 0 && len(positional) == 0 && !hasStdin() && options.fileCidr == "" {
+		gologger.Fatalf("No input provided!\n")
+	}
+
+	var cidrs []string
+	for cidr := range readCidrs(options.cidr, positional, options.fileCidr) {
+		cidrs = append(cidrs, cidr)
+	}
+
+	aggregated, err := mapcidr.Aggregate(cidrs)
+	if err != nil {
+		gologger.Fatalf("%s\n", err)
+	}
+
+	recordchan := make(chan Record)
+	go func() {
+		defer close(recordchan)
+		for _, network := range aggregated {
+			recordchan <- subnetRecord(network)
+		}
+	}()
+
+	writeOutput(options.output, recordchan)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"net"
+	"os"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// containsOptions contains cli options for the contains command
+type containsOptions struct {
+	cidr     cidrFlag
+	fileCidr string
+	silent   bool
+}
+
+func runContains(args []string) {
+	options := &containsOptions{}
+
+	flagSet := flag.NewFlagSet("contains", flag.ExitOnError)
+	flagSet.Var(&options.cidr, "cidr", "CIDR to check against (repeatable)")
+	flagSet.StringVar(&options.fileCidr, "l", "", "File containing CIDR(s) to check against")
+	flagSet.BoolVar(&options.silent, "silent", false, "Silent mode")
+	flagSet.Parse(args)
+
+	configureOutput(options.silent)
+	showBanner()
+
+	positional := flagSet.Args()
+	if len(positional) < 1 {
+		gologger.Fatalf("Usage: mapcidr contains [flags] <ip> [cidr...]\n")
+	}
+	ip := net.ParseIP(positional[0])
+	if ip == nil {
+		gologger.Fatalf("%s is not a valid IP address\n", positional[0])
+	}
+	cidrArgs := positional[1:]
+
+	if len(options.cidr) == 0 && len(cidrArgs) == 0 && options.fileCidr == "" && !hasStdin() {
+		gologger.Fatalf("No input provided!\n")
+	}
+
+	found := false
+	for cidr := range readCidrs(options.cidr, cidrArgs, options.fileCidr) {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			gologger.Fatalf("%s\n", err)
+		}
+		if network.Contains(ip) {
+			found = true
+			gologger.Silentf("%s\n", network.String())
+		}
+	}
+
+	if !found {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/mapcidr"
+)
+
+// excludeOptions contains cli options for the exclude command
+type excludeOptions struct {
+	cidr       cidrFlag
+	fileCidr   string
+	removeFile string
+	output     outputFlag
+	silent     bool
+}
+
+func runExclude(args []string) {
+	options := &excludeOptions{}
+
+	flagSet := flag.NewFlagSet("exclude", flag.ExitOnError)
+	flagSet.Var(&options.cidr, "cidr", "CIDR to process (repeatable)")
+	flagSet.StringVar(&options.fileCidr, "l", "", "File containing CIDR")
+	flagSet.StringVar(&options.removeFile, "remove", "", "File containing CIDR(s) to exclude")
+	flagSet.Var(&options.output, "o", "Output target, e.g. type=json,dest=out.json (repeatable)")
+	flagSet.BoolVar(&options.silent, "silent", false, "Silent mode")
+	flagSet.Parse(args)
+
+	positional := flagSet.Args()
+
+	configureOutput(options.silent)
+	showBanner()
+
+	if len(options.cidr) == 0 && len(positional) == 0 && !hasStdin() && options.fileCidr == "" {
+		gologger.Fatalf("No input provided!\n")
+	}
+	if options.removeFile == "" {
+		gologger.Fatalf("-remove is required\n")
+	}
+
+	var base []string
+	for cidr := range readCidrs(options.cidr, positional, options.fileCidr) {
+		base = append(base, cidr)
+	}
+
+	var remove []string
+	for cidr := range readCidrs(nil, nil, options.removeFile) {
+		remove = append(remove, cidr)
+	}
+
+	remaining, err := mapcidr.Exclude(base, remove)
+	if err != nil {
+		gologger.Fatalf("%s\n", err)
+	}
+
+	recordchan := make(chan Record)
+	go func() {
+		defer close(recordchan)
+		for _, network := range remaining {
+			recordchan <- subnetRecord(network)
+		}
+	}()
+
+	writeOutput(options.output, recordchan)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"net"
+	"sync"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/mapcidr"
+)
+
+// expandOptions contains cli options for the expand command
+type expandOptions struct {
+	cidr     cidrFlag
+	fileCidr string
+	output   outputFlag
+	silent   bool
+}
+
+func runExpand(args []string) {
+	options := &expandOptions{}
+
+	flagSet := flag.NewFlagSet("expand", flag.ExitOnError)
+	flagSet.Var(&options.cidr, "cidr", "CIDR to process (repeatable)")
+	flagSet.StringVar(&options.fileCidr, "l", "", "File containing CIDR")
+	flagSet.Var(&options.output, "o", "Output target, e.g. type=json,dest=out.json (repeatable)")
+	flagSet.BoolVar(&options.silent, "silent", false, "Silent mode")
+	flagSet.Parse(args)
+
+	positional := flagSet.Args()
+
+	configureOutput(options.silent)
+	showBanner()
+
+	if len(options.cidr) == 0 && len(positional) == 0 && !hasStdin() && options.fileCidr == "" {
+		gologger.Fatalf("No input provided!\n")
+	}
+
+	recordchan := make(chan Record)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for cidr := range readCidrs(options.cidr, positional, options.fileCidr) {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				gologger.Fatalf("%s\n", err)
+			}
+
+			// Stream addresses directly onto recordchan instead of
+			// materializing a slice first, so huge IPv6 ranges don't
+			// need to fit in memory up front.
+			err := mapcidr.IPAddressesIter(cidr, func(ip net.IP) bool {
+				recordchan <- Record{IP: ip.String()}
+				return true
+			})
+			if err != nil {
+				gologger.Fatalf("%s\n", err)
+			}
+		}
+		close(recordchan)
+	}()
+
+	writeOutput(options.output, recordchan)
+	wg.Wait()
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/projectdiscovery/gologger"
+)
+
+// hasStdin reports whether data is being piped in on stdin
+func hasStdin() bool {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	if fi.Mode()&os.ModeNamedPipe == 0 {
+		return false
+	}
+	return true
+}
+
+// cidrFlag collects repeated -cidr flags into a slice of CIDRs.
+type cidrFlag []string
+
+func (c *cidrFlag) String() string {
+	return fmt.Sprint([]string(*c))
+}
+
+func (c *cidrFlag) Set(value string) error {
+	*c = append(*c, value)
+	return nil
+}
+
+// readCidrs streams every CIDR supplied via repeatable -cidr flags, free-form
+// positional arguments, stdin and a file list onto a channel, in that order.
+func readCidrs(cidrs cidrFlag, positional []string, fileCidr string) chan string {
+	chancidr := make(chan string)
+
+	go func() {
+		defer close(chancidr)
+
+		for _, cidr := range cidrs {
+			chancidr <- cidr
+		}
+
+		for _, cidr := range positional {
+			chancidr <- cidr
+		}
+
+		if hasStdin() {
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				chancidr <- scanner.Text()
+			}
+		}
+
+		if fileCidr != "" {
+			file, err := os.Open(fileCidr)
+			if err != nil {
+				gologger.Fatalf("%s\n", err)
+			}
+			defer file.Close()
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				chancidr <- scanner.Text()
+			}
+		}
+	}()
+
+	return chancidr
+}
+
+// Record is a single result item flowing through a record channel. Which
+// fields are populated depends on the command that produced it: split,
+// aggregate and exclude populate CIDR/Network/Broadcast/Count, expand
+// populates only IP.
+type Record struct {
+	CIDR      string   `json:"cidr,omitempty"`
+	Network   string   `json:"network,omitempty"`
+	Broadcast string   `json:"broadcast,omitempty"`
+	Count     *big.Int `json:"count,omitempty"`
+	IP        string   `json:"ip,omitempty"`
+}
+
+// Text renders the record the way plain-text output always has: the IP for
+// expansion records, the CIDR otherwise.
+func (r Record) Text() string {
+	if r.IP != "" {
+		return r.IP
+	}
+	return r.CIDR
+}
+
+// outputSpec describes a single -o target, e.g. type=json,dest=out.json
+type outputSpec struct {
+	Type string
+	Dest string
+}
+
+// outputFlag collects repeated -o flags into outputSpecs, buildkit style.
+type outputFlag []outputSpec
+
+func (o *outputFlag) String() string {
+	return fmt.Sprint([]outputSpec(*o))
+}
+
+func (o *outputFlag) Set(value string) error {
+	spec := outputSpec{Type: "text", Dest: "-"}
+
+	for _, kv := range strings.Split(value, ",") {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid output attribute %q, expected key=value", kv)
+		}
+
+		key, val := parts[0], parts[1]
+		switch key {
+		case "type":
+			switch val {
+			case "text", "json", "csv":
+				spec.Type = val
+			default:
+				return fmt.Errorf("unknown output type %q", val)
+			}
+		case "dest":
+			spec.Dest = val
+		default:
+			return fmt.Errorf("unknown output attribute %q", key)
+		}
+	}
+
+	*o = append(*o, spec)
+	return nil
+}
+
+// recordWriter renders records to a single destination in a single format.
+type recordWriter struct {
+	spec      outputSpec
+	file      *os.File
+	csvw      *csv.Writer
+	wroteHead bool
+}
+
+func newRecordWriter(spec outputSpec) (*recordWriter, error) {
+	w := &recordWriter{spec: spec}
+
+	if spec.Dest == "" || spec.Dest == "-" {
+		w.file = os.Stdout
+	} else {
+		f, err := os.Create(spec.Dest)
+		if err != nil {
+			return nil, fmt.Errorf("could not create output file '%s': %s", spec.Dest, err)
+		}
+		w.file = f
+	}
+
+	if spec.Type == "csv" {
+		w.csvw = csv.NewWriter(w.file)
+	}
+
+	return w, nil
+}
+
+func (w *recordWriter) write(record Record) {
+	switch w.spec.Type {
+	case "json":
+		data, err := json.Marshal(record)
+		if err != nil {
+			gologger.Fatalf("%s\n", err)
+		}
+		w.file.Write(append(data, '\n'))
+	case "csv":
+		if !w.wroteHead {
+			w.csvw.Write(recordCSVFields(record, true))
+			w.wroteHead = true
+		}
+		w.csvw.Write(recordCSVFields(record, false))
+	default:
+		fmt.Fprintln(w.file, record.Text())
+	}
+}
+
+func (w *recordWriter) close() {
+	if w.csvw != nil {
+		w.csvw.Flush()
+	}
+	if w.file != nil && w.file != os.Stdout {
+		w.file.Close()
+	}
+}
+
+// recordCSVFields returns either the CSV header row or the CSV data row for
+// a record, depending on whether it is an IP-only (expand) record or a
+// CIDR record (split/aggregate/exclude).
+func recordCSVFields(r Record, header bool) []string {
+	if r.IP != "" || (header && r.CIDR == "") {
+		if header {
+			return []string{"ip"}
+		}
+		return []string{r.IP}
+	}
+	if header {
+		return []string{"cidr", "network", "broadcast", "count"}
+	}
+	return []string{r.CIDR, r.Network, r.Broadcast, r.Count.String()}
+}
+
+// writeOutput fans every record out to each requested output target. With
+// no -o flags given it defaults to a single text writer on stdout.
+func writeOutput(specs []outputSpec, recordchan chan Record) {
+	if len(specs) == 0 {
+		specs = []outputSpec{{Type: "text", Dest: "-"}}
+	}
+
+	writers := make([]*recordWriter, 0, len(specs))
+	for _, spec := range specs {
+		w, err := newRecordWriter(spec)
+		if err != nil {
+			gologger.Fatalf("%s\n", err)
+		}
+		writers = append(writers, w)
+	}
+
+	for record := range recordchan {
+		if record.Text() == "" {
+			continue
+		}
+		for _, w := range writers {
+			w.write(record)
+		}
+	}
+
+	for _, w := range writers {
+		w.close()
+	}
+}
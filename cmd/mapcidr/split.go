@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"net"
+	"sync"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/mapcidr"
+)
+
+// splitOptions contains cli options for the split command
+type splitOptions struct {
+	slices    int
+	hostCount int
+	cidr      cidrFlag
+	fileCidr  string
+	output    outputFlag
+	silent    bool
+}
+
+func runSplit(args []string) {
+	options := &splitOptions{}
+
+	flagSet := flag.NewFlagSet("split", flag.ExitOnError)
+	flagSet.IntVar(&options.slices, "by-count", 0, "Split by CIDR count")
+	flagSet.IntVar(&options.hostCount, "by-host-count", 0, "Split by host count")
+	flagSet.Var(&options.cidr, "cidr", "CIDR to process (repeatable)")
+	flagSet.StringVar(&options.fileCidr, "l", "", "File containing CIDR")
+	flagSet.Var(&options.output, "o", "Output target, e.g. type=json,dest=out.json (repeatable)")
+	flagSet.BoolVar(&options.silent, "silent", false, "Silent mode")
+	flagSet.Parse(args)
+
+	positional := flagSet.Args()
+
+	configureOutput(options.silent)
+	showBanner()
+
+	if len(options.cidr) == 0 && len(positional) == 0 && !hasStdin() && options.fileCidr == "" {
+		gologger.Fatalf("No input provided!\n")
+	}
+	if options.slices > 0 && options.hostCount > 0 {
+		gologger.Fatalf("by-count and by-host-count cant be used together!\n")
+	}
+	if options.slices == 0 && options.hostCount == 0 {
+		gologger.Fatalf("Either -by-count or -by-host-count must be set!\n")
+	}
+
+	recordchan := make(chan Record)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for cidr := range readCidrs(options.cidr, positional, options.fileCidr) {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				gologger.Fatalf("%s\n", err)
+			}
+
+			var (
+				subnets []*net.IPNet
+				err     error
+			)
+			if options.slices > 0 {
+				subnets, err = mapcidr.SplitN(cidr, options.slices)
+			} else {
+				subnets, err = mapcidr.SplitByNumber(cidr, options.hostCount)
+			}
+			if err != nil {
+				gologger.Fatalf("%s\n", err)
+			}
+			for _, subnet := range subnets {
+				recordchan <- subnetRecord(subnet)
+			}
+		}
+		close(recordchan)
+	}()
+
+	writeOutput(options.output, recordchan)
+	wg.Wait()
+}
+
+// subnetRecord builds the Record emitted for a single CIDR block, shared by
+// split, aggregate and exclude.
+func subnetRecord(subnet *net.IPNet) Record {
+	_, broadcast := mapcidr.AddressRange(subnet)
+	return Record{
+		CIDR:      subnet.String(),
+		Network:   subnet.IP.String(),
+		Broadcast: broadcast.String(),
+		Count:     mapcidr.AddressCount(subnet),
+	}
+}
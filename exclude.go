@@ -0,0 +1,94 @@
+package mapcidr
+
+import (
+	"math/big"
+	"net"
+)
+
+// Exclude subtracts every CIDR in remove from the CIDRs in base and returns
+// the minimal set of CIDR blocks covering what's left. IPv4 and IPv6 are
+// handled independently of each other. A remove range fully containing a
+// base range drops it entirely, a disjoint remove range leaves the base
+// range untouched, and a remove range that splits a base range yields its
+// surviving remainders.
+func Exclude(base []string, remove []string) ([]*net.IPNet, error) {
+	baseV4, baseV6, err := rangesByFamily(base)
+	if err != nil {
+		return nil, err
+	}
+	removeV4, removeV6, err := rangesByFamily(remove)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*net.IPNet
+	for _, r := range subtractRanges(mergeRanges(baseV4), mergeRanges(removeV4)) {
+		result = append(result, rangeToCIDRs(r)...)
+	}
+	for _, r := range subtractRanges(mergeRanges(baseV6), mergeRanges(removeV6)) {
+		result = append(result, rangeToCIDRs(r)...)
+	}
+
+	return result, nil
+}
+
+// rangesByFamily parses cidrs and splits the resulting ranges into IPv4
+// and IPv6 buckets.
+func rangesByFamily(cidrs []string) (v4, v6 []ipRange, err error) {
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		r := cidrToRange(network)
+		if r.bits == 32 {
+			v4 = append(v4, r)
+		} else {
+			v6 = append(v6, r)
+		}
+	}
+	return v4, v6, nil
+}
+
+// subtractRanges removes every range in remove (already merged, sorted and
+// disjoint) from every range in base (same precondition), via a linear
+// sweep over both sorted slices.
+func subtractRanges(base, remove []ipRange) []ipRange {
+	var result []ipRange
+	one := big.NewInt(1)
+
+	j := 0
+	for _, b := range base {
+		start := new(big.Int).Set(b.start)
+
+		// remove ranges ending before b also end before every later base range
+		for j < len(remove) && remove[j].end.Cmp(b.start) < 0 {
+			j++
+		}
+
+		for _, r := range remove[j:] {
+			if r.start.Cmp(b.end) > 0 {
+				break // this and every later remove range lie past b
+			}
+
+			if r.start.Cmp(start) > 0 {
+				result = append(result, ipRange{start: start, end: new(big.Int).Sub(r.start, one), bits: b.bits})
+			}
+
+			if r.end.Cmp(start) >= 0 {
+				start = new(big.Int).Add(r.end, one)
+			}
+
+			if start.Cmp(b.end) > 0 {
+				break
+			}
+		}
+
+		if start.Cmp(b.end) <= 0 {
+			result = append(result, ipRange{start: start, end: b.end, bits: b.bits})
+		}
+	}
+
+	return result
+}
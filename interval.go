@@ -0,0 +1,120 @@
+package mapcidr
+
+import (
+	"math/big"
+	"net"
+	"sort"
+)
+
+// ipRange is an inclusive [start, end] address range, expressed as
+// arbitrary-precision integers so the same logic covers IPv4 and IPv6.
+type ipRange struct {
+	start *big.Int
+	end   *big.Int
+	bits  int // 32 for IPv4, 128 for IPv6
+}
+
+// ipToBigInt converts an IP address to its big.Int representation, along
+// with the address family's bit length.
+func ipToBigInt(ip net.IP) (*big.Int, int) {
+	if v4 := ip.To4(); v4 != nil {
+		return new(big.Int).SetBytes(v4), 32
+	}
+	return new(big.Int).SetBytes(ip.To16()), 128
+}
+
+// bigIntToIP converts a big.Int back into an IP address of the given
+// bit length.
+func bigIntToIP(i *big.Int, bits int) net.IP {
+	buf := make([]byte, bits/8)
+	b := i.Bytes()
+	copy(buf[len(buf)-len(b):], b)
+	return net.IP(buf)
+}
+
+// cidrToRange converts a parsed CIDR into its inclusive address range.
+func cidrToRange(network *net.IPNet) ipRange {
+	_, bits := network.Mask.Size()
+	start, _ := ipToBigInt(network.IP.Mask(network.Mask))
+
+	end := new(big.Int).Add(start, AddressCount(network))
+	end.Sub(end, big.NewInt(1))
+
+	return ipRange{start: start, end: end, bits: bits}
+}
+
+// mergeRanges sorts the given ranges by start and merges every pair that
+// overlaps or is adjacent, returning the minimal set of disjoint ranges
+// that covers the same addresses.
+func mergeRanges(ranges []ipRange) []ipRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start.Cmp(ranges[j].start) < 0
+	})
+
+	merged := []ipRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+
+		adjacent := new(big.Int).Add(last.end, big.NewInt(1))
+		if r.start.Cmp(adjacent) > 0 {
+			merged = append(merged, r)
+			continue
+		}
+		if r.end.Cmp(last.end) > 0 {
+			last.end = r.end
+		}
+	}
+
+	return merged
+}
+
+// rangeToCIDRs decomposes an inclusive address range into the minimum
+// number of aligned power-of-two CIDR blocks: at each step it emits the
+// largest prefix whose network address equals the current start and whose
+// broadcast does not exceed the end, then advances start past that block.
+func rangeToCIDRs(r ipRange) []*net.IPNet {
+	var result []*net.IPNet
+
+	one := big.NewInt(1)
+	start := new(big.Int).Set(r.start)
+
+	for start.Cmp(r.end) <= 0 {
+		maxHostBits := r.bits
+		if start.Sign() != 0 {
+			maxHostBits = trailingZeroBits(start, r.bits)
+		}
+
+		remaining := new(big.Int).Sub(r.end, start)
+		remaining.Add(remaining, one)
+
+		blockSize := new(big.Int).Lsh(one, uint(maxHostBits))
+		for blockSize.Cmp(remaining) > 0 {
+			maxHostBits--
+			blockSize.Lsh(one, uint(maxHostBits))
+		}
+
+		result = append(result, &net.IPNet{
+			IP:   bigIntToIP(start, r.bits),
+			Mask: net.CIDRMask(r.bits-maxHostBits, r.bits),
+		})
+
+		start.Add(start, blockSize)
+	}
+
+	return result
+}
+
+// trailingZeroBits returns the number of trailing zero bits in n, capped
+// at maxBits (reached when n is zero).
+func trailingZeroBits(n *big.Int, maxBits int) int {
+	for i := 0; i < maxBits; i++ {
+		if n.Bit(i) != 0 {
+			return i
+		}
+	}
+	return maxBits
+}
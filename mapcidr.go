@@ -0,0 +1,165 @@
+// Package mapcidr implements utilities to process CIDR ranges, such as
+// splitting them into smaller subnets and expanding them into individual
+// IP addresses. Every function handles IPv4 and IPv6 uniformly by working
+// on arbitrary-precision integers internally.
+package mapcidr
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+)
+
+// MaxIPAddresses caps how many addresses IPAddresses will materialize into
+// a slice before returning an error. Ranges larger than this must be walked
+// with IPAddressesIter instead.
+var MaxIPAddresses = 1 << 20
+
+// AddressRange returns the first and last addresses in the given CIDR range.
+func AddressRange(network *net.IPNet) (net.IP, net.IP) {
+	firstIP := network.IP.Mask(network.Mask)
+
+	lastIP := make(net.IP, len(firstIP))
+	copy(lastIP, firstIP)
+	for i := range lastIP {
+		lastIP[i] |= ^network.Mask[i]
+	}
+
+	return firstIP, lastIP
+}
+
+// AddressCount returns the number of addresses in the given CIDR range, as
+// a big.Int since an IPv6 range can hold far more addresses than fit in a
+// uint64.
+func AddressCount(network *net.IPNet) *big.Int {
+	ones, bits := network.Mask.Size()
+	return new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+}
+
+// SplitN splits the given CIDR into N equally sized subnets, using the
+// shortest prefix that yields at least N blocks and returning the first
+// N of them. When N is not a power of two, the addresses after the N-th block
+// are not covered by any returned subnet.
+func SplitN(cidr string, slices int) ([]*net.IPNet, error) {
+	if slices <= 0 {
+		return nil, fmt.Errorf("slices must be greater than zero")
+	}
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	ones, bits := network.Mask.Size()
+	target := big.NewInt(int64(slices))
+
+	newPrefix := ones
+	for new(big.Int).Lsh(big.NewInt(1), uint(newPrefix-ones)).Cmp(target) < 0 {
+		newPrefix++
+		if newPrefix > bits {
+			return nil, fmt.Errorf("cannot split %s into %d slices", cidr, slices)
+		}
+	}
+
+	return splitInto(network, newPrefix, bits, int64(slices))
+}
+
+// SplitByNumber splits the given CIDR into the smallest number of subnets
+// that can each hold at least hostCount addresses.
+func SplitByNumber(cidr string, hostCount int) ([]*net.IPNet, error) {
+	if hostCount <= 0 {
+		return nil, fmt.Errorf("hostCount must be greater than zero")
+	}
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	ones, bits := network.Mask.Size()
+	target := big.NewInt(int64(hostCount))
+
+	newPrefix := bits
+	for new(big.Int).Lsh(big.NewInt(1), uint(bits-newPrefix)).Cmp(target) < 0 {
+		newPrefix--
+		if newPrefix < ones {
+			return nil, fmt.Errorf("%s is too small to fit %d hosts per subnet", cidr, hostCount)
+		}
+	}
+
+	slices := new(big.Int).Lsh(big.NewInt(1), uint(newPrefix-ones))
+	if !slices.IsInt64() {
+		return nil, fmt.Errorf("%s splits into too many subnets to enumerate", cidr)
+	}
+
+	return splitInto(network, newPrefix, bits, slices.Int64())
+}
+
+// splitInto carves network into `count` equally sized subnets of the given
+// newPrefix length.
+func splitInto(network *net.IPNet, newPrefix, bits int, count int64) ([]*net.IPNet, error) {
+	step := new(big.Int).Lsh(big.NewInt(1), uint(bits-newPrefix))
+	base, _ := ipToBigInt(network.IP.Mask(network.Mask))
+
+	subnets := make([]*net.IPNet, 0, count)
+	for i := int64(0); i < count; i++ {
+		offset := new(big.Int).Mul(step, big.NewInt(i))
+		addr := new(big.Int).Add(base, offset)
+		subnets = append(subnets, &net.IPNet{
+			IP:   bigIntToIP(addr, bits),
+			Mask: net.CIDRMask(newPrefix, bits),
+		})
+	}
+
+	return subnets, nil
+}
+
+// IPAddresses returns every individual IP address contained in the given
+// CIDR. It returns an error if the CIDR holds more than MaxIPAddresses
+// addresses; use IPAddressesIter to stream through larger ranges instead.
+func IPAddresses(cidr string) ([]string, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	count := AddressCount(network)
+	if count.Cmp(big.NewInt(int64(MaxIPAddresses))) > 0 {
+		return nil, fmt.Errorf("%s holds more than %d addresses, use IPAddressesIter to stream them instead", cidr, MaxIPAddresses)
+	}
+
+	ips := make([]string, 0, count.Int64())
+	err = IPAddressesIter(cidr, func(ip net.IP) bool {
+		ips = append(ips, ip.String())
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ips, nil
+}
+
+// IPAddressesIter streams every individual IP address contained in cidr to
+// fn, in order, stopping early if fn returns false. Unlike IPAddresses it
+// never materializes the whole range in memory, so it is not subject to
+// MaxIPAddresses.
+func IPAddressesIter(cidr string, fn func(net.IP) bool) error {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return err
+	}
+
+	first, last := AddressRange(network)
+	start, bits := ipToBigInt(first)
+	end, _ := ipToBigInt(last)
+
+	one := big.NewInt(1)
+	for cur := new(big.Int).Set(start); cur.Cmp(end) <= 0; cur.Add(cur, one) {
+		if !fn(bigIntToIP(cur, bits)) {
+			break
+		}
+	}
+
+	return nil
+}